@@ -16,12 +16,14 @@
 package pages
 
 import (
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"math"
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -29,8 +31,20 @@ import (
 	"github.com/golang/glog"
 	"github.com/google/cadvisor/info"
 	"github.com/google/cadvisor/manager"
+	"github.com/google/cadvisor/pages/image"
 )
 
+// imageResolver resolves image metadata (digest, labels, layer count) for
+// the "Image" section of the containers page. It's nil, and the section is
+// omitted, unless wired up by the binary embedding this package.
+var imageResolver image.Resolver
+
+// SetImageResolver installs the resolver used to populate the "Image"
+// section of the containers page. Pass nil to disable the section again.
+func SetImageResolver(resolver image.Resolver) {
+	imageResolver = resolver
+}
+
 const ContainersPage = "/containers/"
 
 // from http://golang.org/doc/effective_go.html#constants
@@ -99,6 +113,14 @@ var funcMap = template.FuncMap{
 	"getColdMemoryPercent":  getColdMemoryPercent,
 	"getFsStats":            getFsStats,
 	"getFsUsagePercent":     getFsUsagePercent,
+	"getFsSeries":           getFsSeries,
+	"getFsLatencyBuckets":   getFsLatencyBuckets,
+	"printJobCpuRate":       printJobCpuRate,
+	"getPrivateWorkingSet":  getPrivateWorkingSet,
+	"getCommitCharge":       getCommitCharge,
+	"getVhdUsage":           getVhdUsage,
+	"imageDigestShort":      imageDigestShort,
+	"formatImageLabels":     formatImageLabels,
 }
 
 func printMask(mask string, numCores int) interface{} {
@@ -219,20 +241,232 @@ func getFsUsagePercent(limit, used uint64) uint64 {
 	return uint64((float64(used) / float64(limit)) * 100)
 }
 
-func serveContainersPage(m manager.Manager, w http.ResponseWriter, u *url.URL) error {
+const (
+	// defaultTargetPoints is the number of points shown on the page when the
+	// caller doesn't specify a resolution explicitly.
+	defaultTargetPoints = 60
+	// maxTargetPoints bounds how many buckets a single request can ask for,
+	// so a tiny "?resolution=" can't make downsampleStats try to allocate
+	// an unbounded number of statsBucket entries.
+	maxTargetPoints = 1000
+	// minResolution bounds how fine-grained a "?resolution=" can be.
+	minResolution = time.Second
+)
+
+// aggregate summarizes a series of samples that fell into a single bucket.
+type aggregate struct {
+	Mean float64
+	Min  float64
+	Max  float64
+}
+
+func newAggregate(values []float64) aggregate {
+	if len(values) == 0 {
+		return aggregate{}
+	}
+	sum, min, max := values[0], values[0], values[0]
+	for _, v := range values[1:] {
+		sum += v
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	return aggregate{Mean: sum / float64(len(values)), Min: min, Max: max}
+}
+
+// fsAggregate is the per-device filesystem usage summary for a bucket.
+type fsAggregate struct {
+	Device string
+	Usage  aggregate
+}
+
+// statsBucket is a single downsampled point on the page's time series
+// charts, summarizing every raw sample that fell within its time range.
+type statsBucket struct {
+	Timestamp      time.Time
+	CpuUsage       aggregate
+	MemoryUsage    aggregate
+	NetworkRxBytes aggregate
+	NetworkTxBytes aggregate
+	Filesystem     []fsAggregate
+}
+
+// downsampleStats buckets stats (ordered oldest-to-newest) into roughly
+// targetPoints buckets and summarizes CPU, memory, network, and filesystem
+// usage in each with mean/min/max aggregation.
+func downsampleStats(stats []*info.ContainerStats, targetPoints int) []statsBucket {
+	if len(stats) == 0 || targetPoints <= 0 {
+		return nil
+	}
+	bucketSize := int(math.Ceil(float64(len(stats)) / float64(targetPoints)))
+	if bucketSize < 1 {
+		bucketSize = 1
+	}
+	// The number of buckets we'll actually produce is bounded by the number
+	// of raw samples, regardless of how large targetPoints is; size the
+	// allocation off that instead of trusting the caller-supplied value.
+	allocSize := targetPoints
+	if allocSize > len(stats) {
+		allocSize = len(stats)
+	}
+	buckets := make([]statsBucket, 0, allocSize)
+	for i := 0; i < len(stats); i += bucketSize {
+		end := i + bucketSize
+		if end > len(stats) {
+			end = len(stats)
+		}
+		buckets = append(buckets, aggregateBucket(stats[i:end]))
+	}
+	return buckets
+}
+
+func aggregateBucket(chunk []*info.ContainerStats) statsBucket {
+	cpu := make([]float64, 0, len(chunk))
+	mem := make([]float64, 0, len(chunk))
+	rx := make([]float64, 0, len(chunk))
+	tx := make([]float64, 0, len(chunk))
+	fsUsage := make(map[string][]float64)
+
+	for _, s := range chunk {
+		cpu = append(cpu, float64(s.Cpu.Usage.Total))
+		mem = append(mem, float64(s.Memory.Usage))
+		var rxBytes, txBytes uint64
+		for _, iface := range s.Network.Interfaces {
+			rxBytes += iface.RxBytes
+			txBytes += iface.TxBytes
+		}
+		rx = append(rx, float64(rxBytes))
+		tx = append(tx, float64(txBytes))
+		for _, fs := range s.Filesystem {
+			fsUsage[fs.Device] = append(fsUsage[fs.Device], float64(fs.Usage))
+		}
+	}
+
+	devices := make([]string, 0, len(fsUsage))
+	for device := range fsUsage {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+	fsAggregates := make([]fsAggregate, 0, len(devices))
+	for _, device := range devices {
+		fsAggregates = append(fsAggregates, fsAggregate{Device: device, Usage: newAggregate(fsUsage[device])})
+	}
+
+	return statsBucket{
+		Timestamp:      chunk[len(chunk)-1].Timestamp,
+		CpuUsage:       newAggregate(cpu),
+		MemoryUsage:    newAggregate(mem),
+		NetworkRxBytes: newAggregate(rx),
+		NetworkTxBytes: newAggregate(tx),
+		Filesystem:     fsAggregates,
+	}
+}
+
+// imageDigestShort truncates a "sha256:..." digest to a short form for
+// display, the way `docker images --digests` does.
+func imageDigestShort(digest string) string {
+	const prefix = "sha256:"
+	if strings.HasPrefix(digest, prefix) {
+		digest = digest[len(prefix):]
+	}
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	return digest
+}
+
+// formatImageLabels renders an image's labels as "key=value" lines.
+func formatImageLabels(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	formatted := make([]string, 0, len(keys))
+	for _, k := range keys {
+		formatted = append(formatted, fmt.Sprintf("%s=%s", k, labels[k]))
+	}
+	return formatted
+}
+
+// parseDurationParam parses the named query parameter as a time.Duration,
+// returning def if the parameter is absent.
+func parseDurationParam(query url.Values, key string, def time.Duration) (time.Duration, error) {
+	val := query.Get(key)
+	if val == "" {
+		return def, nil
+	}
+	return time.ParseDuration(val)
+}
+
+// wantsJSON reports whether the request asked for a JSON representation of
+// the containers page, either via the "Accept" header or a "?format=json"
+// query parameter.
+func wantsJSON(r *http.Request, u *url.URL) bool {
+	if u.Query().Get("format") == "json" {
+		return true
+	}
+	for _, accept := range r.Header["Accept"] {
+		for _, part := range strings.Split(accept, ",") {
+			if strings.HasPrefix(strings.TrimSpace(part), "application/json") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func serveContainersPage(m manager.Manager, w http.ResponseWriter, r *http.Request) error {
+	u := r.URL
 	start := time.Now()
 
 	// The container name is the path after the handler
 	containerName := u.Path[len(ContainersPage)-1:]
 
+	// A "?window=1h&resolution=15s" request asks for a specific time range
+	// instead of the default live view, with the raw samples bucketed down
+	// to a more manageable number of points before rendering.
+	query := u.Query()
+	window, err := parseDurationParam(query, "window", 0)
+	if err != nil {
+		return fmt.Errorf("invalid window parameter: %v", err)
+	}
+	resolution, err := parseDurationParam(query, "resolution", 0)
+	if err != nil {
+		return fmt.Errorf("invalid resolution parameter: %v", err)
+	}
+
 	// Get the container.
-	reqParams := info.ContainerInfoRequest{
-		NumStats: 60,
+	var reqParams info.ContainerInfoRequest
+	var windowStart, windowEnd time.Time
+	if window > 0 {
+		windowEnd = time.Now()
+		windowStart = windowEnd.Add(-window)
+		reqParams = info.ContainerInfoRequest{Start: windowStart, End: windowEnd}
+	} else {
+		reqParams = info.ContainerInfoRequest{NumStats: 60}
 	}
 	cont, err := m.GetContainerInfo(containerName, &reqParams)
 	if err != nil {
 		return fmt.Errorf("Failed to get container %q with error: %v", containerName, err)
 	}
+
+	targetPoints := defaultTargetPoints
+	if window > 0 && resolution > 0 {
+		if resolution < minResolution {
+			resolution = minResolution
+		}
+		if points := int(window / resolution); points > 0 {
+			targetPoints = points
+		}
+	}
+	if targetPoints > maxTargetPoints {
+		targetPoints = maxTargetPoints
+	}
+	statsBuckets := downsampleStats(cont.Stats, targetPoints)
 	displayName := getContainerDisplayName(cont.ContainerReference)
 
 	// Get the MachineInfo
@@ -268,6 +502,18 @@ func serveContainersPage(m manager.Manager, w http.ResponseWriter, u *url.URL) e
 		})
 	}
 
+	// Resolve the image backing this container, if an image resolver has
+	// been wired up. A resolution failure (e.g. the image has since been
+	// removed from the local store) just omits the Image section.
+	var imageInfo *image.Info
+	if imageResolver != nil && cont.Spec.Image != "" {
+		imageInfo, err = imageResolver.Resolve(cont.Spec.Image)
+		if err != nil {
+			glog.Warningf("Failed to resolve image %q for container %q: %v", cont.Spec.Image, cont.Name, err)
+			imageInfo = nil
+		}
+	}
+
 	data := &pageData{
 		DisplayName:        displayName,
 		ContainerName:      cont.Name,
@@ -281,8 +527,34 @@ func serveContainersPage(m manager.Manager, w http.ResponseWriter, u *url.URL) e
 		MemoryAvailable:    cont.Spec.HasMemory,
 		NetworkAvailable:   cont.Spec.HasNetwork,
 		FsAvailable:        cont.Spec.HasFilesystem,
+		WindowStart:        windowStart,
+		WindowEnd:          windowEnd,
+		Resolution:         resolution,
+		StatsBuckets:       statsBuckets,
+		Platform:           platformFor(cont.Stats),
+		ImageRef:           cont.Spec.Image,
+	}
+	if imageInfo != nil {
+		data.ImageDigest = imageInfo.Digest
+		data.ImageLabels = imageInfo.Labels
+		data.ImageLayers = imageInfo.Layers
+	}
+	if wantsJSON(r, u) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			glog.Errorf("Failed to encode page data as JSON: %s", err)
+		}
+		glog.V(1).Infof("Request took %s", time.Since(start))
+		return nil
+	}
+
+	// windowsPageTemplate is compiled from containers_windows.html, the
+	// Windows/HCS counterpart of the Linux containers.html template.
+	activeTemplate := pageTemplate
+	if data.Platform == platformWindows {
+		activeTemplate = windowsPageTemplate
 	}
-	err = pageTemplate.Execute(w, data)
+	err = activeTemplate.Execute(w, data)
 	if err != nil {
 		glog.Errorf("Failed to apply template: %s", err)
 	}
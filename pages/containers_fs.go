@@ -0,0 +1,242 @@
+// Copyright 2014 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Per-filesystem drill-down page for /containers/{name}/fs/{device}
+package pages
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/cadvisor/info"
+	"github.com/google/cadvisor/manager"
+)
+
+//go:embed assets/html/static/pages/containers_fs.html
+var fsPageHTML string
+
+// fsPageTemplate renders the per-filesystem drill-down page.
+var fsPageTemplate = template.Must(template.New("containersFsTemplate").Funcs(funcMap).Parse(fsPageHTML))
+
+// fsPageSuffix marks where the device name starts in the URL path, e.g.
+// "/containers/docker/abcd/fs/sda1" -> container "docker/abcd", device "sda1".
+const fsPageSuffix = "/fs/"
+
+// isFsPagePath reports whether p names a filesystem drill-down page: the
+// last "/fs/" in the path must be followed by exactly one more path
+// segment (the device), not by a container path that merely happens to
+// contain "/fs/" as a non-final segment (e.g. ".../kubepods/fs/burstable/abcd").
+func isFsPagePath(p string) bool {
+	idx := strings.LastIndex(p, fsPageSuffix)
+	if idx == -1 {
+		return false
+	}
+	device := p[idx+len(fsPageSuffix):]
+	return device != "" && !strings.Contains(device, "/")
+}
+
+// fsSeriesPoint is a single point on the per-device usage/IOPS time series.
+type fsSeriesPoint struct {
+	Timestamp       time.Time
+	UsedBytes       uint64
+	AvailableBytes  uint64
+	ReadsPerSecond  float64
+	WritesPerSecond float64
+}
+
+// fsLatencyBuckets holds the latency percentiles derived from successive
+// FsStats samples for a device over the selected window.
+type fsLatencyBuckets struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+}
+
+// fsPageData is the data handed to the filesystem drill-down template.
+type fsPageData struct {
+	DisplayName      string
+	ContainerName    string
+	Device           string
+	ParentContainers []link
+	MachineInfo      *info.MachineInfo
+	Series           []fsSeriesPoint
+	LatencyBuckets   fsLatencyBuckets
+}
+
+// findFsStats returns the FsStats entry for device within stats, or nil if
+// the device isn't present in that sample.
+func findFsStats(stats *info.ContainerStats, device string) *info.FsStats {
+	for i := range stats.Filesystem {
+		if stats.Filesystem[i].Device == device {
+			return &stats.Filesystem[i]
+		}
+	}
+	return nil
+}
+
+// getFsSeries builds the used/available bytes and IOPS time series for a
+// single device out of the raw per-container stats.
+func getFsSeries(stats []*info.ContainerStats, device string) []fsSeriesPoint {
+	series := make([]fsSeriesPoint, 0, len(stats))
+	var prev *info.FsStats
+	var prevTime time.Time
+	for _, s := range stats {
+		fs := findFsStats(s, device)
+		if fs == nil {
+			continue
+		}
+		point := fsSeriesPoint{
+			Timestamp:      s.Timestamp,
+			UsedBytes:      fs.Usage,
+			AvailableBytes: fs.Available,
+		}
+		if prev != nil {
+			elapsed := s.Timestamp.Sub(prevTime).Seconds()
+			if elapsed > 0 {
+				point.ReadsPerSecond = float64(fs.ReadsCompleted-prev.ReadsCompleted) / elapsed
+				point.WritesPerSecond = float64(fs.WritesCompleted-prev.WritesCompleted) / elapsed
+			}
+		}
+		series = append(series, point)
+		prev = fs
+		prevTime = s.Timestamp
+	}
+	return series
+}
+
+// getFsLatencyBuckets derives p50/p90/p99 average per-IO latency for device
+// from the change in cumulative IO time and IO count between samples.
+func getFsLatencyBuckets(stats []*info.ContainerStats, device string) fsLatencyBuckets {
+	var latencies []float64
+	var prev *info.FsStats
+	for _, s := range stats {
+		fs := findFsStats(s, device)
+		if fs == nil {
+			continue
+		}
+		if prev != nil {
+			deltaOps := (fs.ReadsCompleted - prev.ReadsCompleted) + (fs.WritesCompleted - prev.WritesCompleted)
+			deltaTimeMs := (fs.ReadTime - prev.ReadTime) + (fs.WriteTime - prev.WriteTime)
+			if deltaOps > 0 {
+				latencies = append(latencies, float64(deltaTimeMs)/float64(deltaOps))
+			}
+		}
+		prev = fs
+	}
+	return fsLatencyBuckets{
+		P50: latencyPercentile(latencies, 0.50),
+		P90: latencyPercentile(latencies, 0.90),
+		P99: latencyPercentile(latencies, 0.99),
+	}
+}
+
+// latencyPercentile returns the p-th percentile (0 < p <= 1) of latenciesMs,
+// a slice of millisecond latencies, as a time.Duration.
+func latencyPercentile(latenciesMs []float64, p float64) time.Duration {
+	if len(latenciesMs) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), latenciesMs...)
+	sort.Float64s(sorted)
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return time.Duration(sorted[index] * float64(time.Millisecond))
+}
+
+func serveContainerFsPage(m manager.Manager, w http.ResponseWriter, r *http.Request) error {
+	start := time.Now()
+	u := r.URL
+
+	if !isFsPagePath(u.Path) {
+		return fmt.Errorf("invalid filesystem page path %q", u.Path)
+	}
+	idx := strings.LastIndex(u.Path, fsPageSuffix)
+	containerName := u.Path[len(ContainersPage)-1 : idx]
+	device := u.Path[idx+len(fsPageSuffix):]
+
+	query := u.Query()
+	window, err := parseDurationParam(query, "window", time.Hour)
+	if err != nil {
+		return fmt.Errorf("invalid window parameter: %v", err)
+	}
+	windowEnd := time.Now()
+	windowStart := windowEnd.Add(-window)
+
+	cont, err := m.GetContainerInfo(containerName, &info.ContainerInfoRequest{Start: windowStart, End: windowEnd})
+	if err != nil {
+		return fmt.Errorf("Failed to get container %q with error: %v", containerName, err)
+	}
+	displayName := getContainerDisplayName(cont.ContainerReference)
+
+	machineInfo, err := m.GetMachineInfo()
+	if err != nil {
+		return err
+	}
+
+	pathParts := strings.Split(string(cont.Name), "/")
+	parentContainers := make([]link, 0, len(pathParts))
+	parentContainers = append(parentContainers, link{
+		Text: "root",
+		Link: ContainersPage,
+	})
+	for i := 1; i < len(pathParts); i++ {
+		if pathParts[i] == "" {
+			continue
+		}
+		parentContainers = append(parentContainers, link{
+			Text: pathParts[i],
+			Link: path.Join(ContainersPage, path.Join(pathParts[1:i+1]...)),
+		})
+	}
+
+	data := &fsPageData{
+		DisplayName:      displayName,
+		ContainerName:    cont.Name,
+		Device:           device,
+		ParentContainers: parentContainers,
+		MachineInfo:      machineInfo,
+		Series:           getFsSeries(cont.Stats, device),
+		LatencyBuckets:   getFsLatencyBuckets(cont.Stats, device),
+	}
+
+	if wantsJSON(r, u) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			glog.Errorf("Failed to encode filesystem page data as JSON: %s", err)
+		}
+		glog.V(1).Infof("Request took %s", time.Since(start))
+		return nil
+	}
+
+	if err := fsPageTemplate.Execute(w, data); err != nil {
+		glog.Errorf("Failed to apply filesystem template: %s", err)
+	}
+
+	glog.V(1).Infof("Request took %s", time.Since(start))
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2014 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/cadvisor/info"
+)
+
+func TestIsFsPagePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/containers/docker/abcd/fs/sda1", true},
+		{"/containers/fs/sda1", true},
+		{"/containers/docker", false},
+		{"/containers/kubepods/fs/burstable/abcd", false},
+		{"/containers/docker/abcd/fs/", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isFsPagePath(tt.path); got != tt.want {
+				t.Errorf("isFsPagePath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	tests := []struct {
+		name string
+		ms   []float64
+		p    float64
+		want time.Duration
+	}{
+		{"empty", nil, 0.5, 0},
+		{"single", []float64{5}, 0.99, 5 * time.Millisecond},
+		{"p50 of four", []float64{1, 2, 3, 4}, 0.5, 2 * time.Millisecond},
+		{"p99 of four", []float64{1, 2, 3, 4}, 0.99, 4 * time.Millisecond},
+		{"unsorted input", []float64{4, 1, 3, 2}, 0.25, 1 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := latencyPercentile(tt.ms, tt.p); got != tt.want {
+				t.Errorf("latencyPercentile(%v, %v) = %v, want %v", tt.ms, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func fsStatsAt(t time.Time, device string, reads, writes, readTimeMs, writeTimeMs uint64) *info.ContainerStats {
+	return &info.ContainerStats{
+		Timestamp: t,
+		Filesystem: []info.FsStats{
+			{
+				Device:          device,
+				ReadsCompleted:  reads,
+				WritesCompleted: writes,
+				ReadTime:        readTimeMs,
+				WriteTime:       writeTimeMs,
+			},
+		},
+	}
+}
+
+func TestGetFsLatencyBuckets(t *testing.T) {
+	base := time.Unix(0, 0)
+	stats := []*info.ContainerStats{
+		fsStatsAt(base, "sda1", 0, 0, 0, 0),
+		fsStatsAt(base.Add(time.Second), "sda1", 10, 10, 100, 100),
+	}
+	buckets := getFsLatencyBuckets(stats, "sda1")
+	want := 10 * time.Millisecond // 200ms of IO time over 20 ops
+	if buckets.P50 != want || buckets.P90 != want || buckets.P99 != want {
+		t.Errorf("getFsLatencyBuckets(...) = %+v, want all buckets = %v", buckets, want)
+	}
+}
+
+func TestGetFsLatencyBucketsIgnoresOtherDevices(t *testing.T) {
+	base := time.Unix(0, 0)
+	stats := []*info.ContainerStats{
+		fsStatsAt(base, "sda1", 0, 0, 0, 0),
+		fsStatsAt(base.Add(time.Second), "sda1", 10, 0, 100, 0),
+	}
+	if got := getFsLatencyBuckets(stats, "sdb1"); got != (fsLatencyBuckets{}) {
+		t.Errorf("getFsLatencyBuckets(..., %q) = %+v, want zero value", "sdb1", got)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2014 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/cadvisor/info"
+)
+
+func statsAt(t time.Time, memUsage uint64) *info.ContainerStats {
+	return &info.ContainerStats{
+		Timestamp: t,
+		Memory:    info.MemoryStats{Usage: memUsage},
+	}
+}
+
+func TestNewAggregate(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   aggregate
+	}{
+		{"empty", nil, aggregate{}},
+		{"single", []float64{5}, aggregate{Mean: 5, Min: 5, Max: 5}},
+		{"mixed", []float64{1, 2, 3, 4}, aggregate{Mean: 2.5, Min: 1, Max: 4}},
+		{"unsorted", []float64{10, 1, 5}, aggregate{Mean: 16.0 / 3, Min: 1, Max: 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newAggregate(tt.values)
+			if got != tt.want {
+				t.Errorf("newAggregate(%v) = %+v, want %+v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownsampleStatsBucketCount(t *testing.T) {
+	base := time.Unix(0, 0)
+	stats := make([]*info.ContainerStats, 100)
+	for i := range stats {
+		stats[i] = statsAt(base.Add(time.Duration(i)*time.Second), uint64(i))
+	}
+
+	tests := []struct {
+		name         string
+		targetPoints int
+		wantBuckets  int
+	}{
+		{"fewer points than samples", 10, 10},
+		{"more points than samples", 1000, 100},
+		{"exact match", 100, 100},
+		{"zero target", 0, 0},
+		{"negative target", -5, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := downsampleStats(stats, tt.targetPoints)
+			if len(buckets) != tt.wantBuckets {
+				t.Errorf("downsampleStats(..., %d) returned %d buckets, want %d", tt.targetPoints, len(buckets), tt.wantBuckets)
+			}
+		})
+	}
+}
+
+func TestDownsampleStatsNoSamples(t *testing.T) {
+	if got := downsampleStats(nil, 60); got != nil {
+		t.Errorf("downsampleStats(nil, 60) = %v, want nil", got)
+	}
+}
+
+func TestDownsampleStatsAggregatesMemory(t *testing.T) {
+	base := time.Unix(0, 0)
+	stats := []*info.ContainerStats{
+		statsAt(base, 10),
+		statsAt(base.Add(time.Second), 20),
+		statsAt(base.Add(2*time.Second), 30),
+	}
+
+	buckets := downsampleStats(stats, 1)
+	if len(buckets) != 1 {
+		t.Fatalf("downsampleStats(..., 1) returned %d buckets, want 1", len(buckets))
+	}
+	want := aggregate{Mean: 20, Min: 10, Max: 30}
+	if buckets[0].MemoryUsage != want {
+		t.Errorf("MemoryUsage = %+v, want %+v", buckets[0].MemoryUsage, want)
+	}
+}
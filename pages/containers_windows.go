@@ -0,0 +1,147 @@
+// Copyright 2014 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Windows/HCS template helpers for /containers/. These mirror the Linux
+// helpers in containers.go but read the job-object and VHD-backed stats
+// that hcsshim-based runtimes report instead of cgroup/working-set data.
+//
+// The info package doesn't have first-class Windows stats types yet, so
+// these are read out of the generic info.ContainerStats.CustomMetrics
+// surface (the same extension point the custom-metrics collector uses)
+// under the well-known keys below, rather than inventing new fields on a
+// package this one doesn't own.
+package pages
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+
+	"github.com/google/cadvisor/info"
+)
+
+// Well-known info.ContainerStats.CustomMetrics keys an HCS-aware collector
+// is expected to populate for a Windows container.
+const (
+	metricJobCpuRate        = "windows/jobobject/cpu_rate"
+	metricPrivateWorkingSet = "windows/memory/private_working_set_bytes"
+	metricCommitBytes       = "windows/memory/commit_bytes"
+	metricCommitLimit       = "windows/memory/commit_limit_bytes"
+	metricVhdUsedBytes      = "windows/filesystem/vhd_used_bytes"
+	metricVhdSizeBytes      = "windows/filesystem/vhd_size_bytes"
+)
+
+// platform identifies which set of template helpers/partials a container
+// page should use.
+type platform string
+
+const (
+	platformLinux   platform = "linux"
+	platformWindows platform = "windows"
+)
+
+// windowsMetricKeys are the CustomMetrics keys an HCS-aware collector
+// populates for a Windows container; their mere presence in the latest
+// sample is what routes a container to the Windows template, since
+// nothing else in a platform-agnostic ContainerSpec currently says "this
+// is Windows".
+var windowsMetricKeys = []string{
+	metricJobCpuRate,
+	metricPrivateWorkingSet,
+	metricCommitBytes,
+	metricVhdUsedBytes,
+}
+
+// platformFor derives the platform a container is running on from its
+// latest stats: if the Windows-specific CustomMetrics the collector below
+// reads from are present, the container is Windows/HCS-backed.
+func platformFor(stats []*info.ContainerStats) platform {
+	if len(stats) == 0 {
+		return platformLinux
+	}
+	latest := stats[len(stats)-1]
+	for _, key := range windowsMetricKeys {
+		if _, ok := latest.CustomMetrics[key]; ok {
+			return platformWindows
+		}
+	}
+	return platformLinux
+}
+
+// latestCustomMetric returns the most recent value recorded under name in
+// stats' CustomMetrics, and whether any value was present at all.
+func latestCustomMetric(stats []*info.ContainerStats, name string) (float64, bool) {
+	if len(stats) == 0 {
+		return 0, false
+	}
+	values := stats[len(stats)-1].CustomMetrics[name]
+	if len(values) == 0 {
+		return 0, false
+	}
+	return values[len(values)-1].FloatValue, true
+}
+
+// printJobCpuRate renders a job object's CPU rate (parts per 10,000, per the
+// HCS API) as a percentage string, mirroring printCores for the Linux path.
+func printJobCpuRate(stats []*info.ContainerStats) string {
+	rate, ok := latestCustomMetric(stats, metricJobCpuRate)
+	if !ok {
+		return "0.00"
+	}
+	return fmt.Sprintf("%.2f", rate/100)
+}
+
+// getPrivateWorkingSet returns the latest private working set in megabytes,
+// the Windows analogue of getHotMemoryPercent's "hot" working set.
+func getPrivateWorkingSet(stats []*info.ContainerStats) float64 {
+	bytes, ok := latestCustomMetric(stats, metricPrivateWorkingSet)
+	if !ok {
+		return 0
+	}
+	return toMegabytes(uint64(bytes))
+}
+
+// getCommitCharge returns the latest commit charge as a percentage of the
+// container's commit limit, the Windows analogue of getMemoryUsagePercent.
+func getCommitCharge(stats []*info.ContainerStats) int {
+	commit, ok := latestCustomMetric(stats, metricCommitBytes)
+	if !ok {
+		return 0
+	}
+	limit, ok := latestCustomMetric(stats, metricCommitLimit)
+	if !ok || limit == 0 {
+		return 0
+	}
+	return int((commit * 100) / limit)
+}
+
+// getVhdUsage returns the usage percentage of a VHD-backed filesystem, the
+// Windows analogue of getFsUsagePercent.
+func getVhdUsage(stats []*info.ContainerStats) uint64 {
+	used, ok := latestCustomMetric(stats, metricVhdUsedBytes)
+	if !ok {
+		return 0
+	}
+	size, ok := latestCustomMetric(stats, metricVhdSizeBytes)
+	if !ok || size == 0 {
+		return 0
+	}
+	return uint64((used * 100) / size)
+}
+
+//go:embed assets/html/static/pages/containers_windows.html
+var windowsPageHTML string
+
+// windowsPageTemplate is the Windows/HCS counterpart of pageTemplate.
+var windowsPageTemplate = template.Must(template.New("containersWindowsTemplate").Funcs(funcMap).Parse(windowsPageHTML))
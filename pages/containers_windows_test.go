@@ -0,0 +1,73 @@
+// Copyright 2014 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pages
+
+import (
+	"testing"
+
+	"github.com/google/cadvisor/info"
+)
+
+func windowsStats(metrics map[string]float64) []*info.ContainerStats {
+	custom := make(map[string][]info.MetricVal, len(metrics))
+	for name, v := range metrics {
+		custom[name] = []info.MetricVal{{FloatValue: v}}
+	}
+	return []*info.ContainerStats{{CustomMetrics: custom}}
+}
+
+func TestPlatformFor(t *testing.T) {
+	if got := platformFor(nil); got != platformLinux {
+		t.Errorf("platformFor(nil) = %q, want %q", got, platformLinux)
+	}
+	linux := windowsStats(nil)
+	if got := platformFor(linux); got != platformLinux {
+		t.Errorf("platformFor(%+v) = %q, want %q", linux, got, platformLinux)
+	}
+	windows := windowsStats(map[string]float64{metricJobCpuRate: 1234})
+	if got := platformFor(windows); got != platformWindows {
+		t.Errorf("platformFor(%+v) = %q, want %q", windows, got, platformWindows)
+	}
+}
+
+func TestGetCommitCharge(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics map[string]float64
+		want    int
+	}{
+		{"no metrics", nil, 0},
+		{"missing limit", map[string]float64{metricCommitBytes: 100}, 0},
+		{"zero limit", map[string]float64{metricCommitBytes: 100, metricCommitLimit: 0}, 0},
+		{"half used", map[string]float64{metricCommitBytes: 50, metricCommitLimit: 100}, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getCommitCharge(windowsStats(tt.metrics)); got != tt.want {
+				t.Errorf("getCommitCharge(...) = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetVhdUsage(t *testing.T) {
+	stats := windowsStats(map[string]float64{metricVhdUsedBytes: 25, metricVhdSizeBytes: 100})
+	if got := getVhdUsage(stats); got != 25 {
+		t.Errorf("getVhdUsage(...) = %d, want 25", got)
+	}
+	if got := getVhdUsage(nil); got != 0 {
+		t.Errorf("getVhdUsage(nil) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2014 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+)
+
+// DockerImageInspector is the subset of *client.Client this package needs,
+// so DockerResolver can be tested against a fake instead of a real daemon.
+type DockerImageInspector interface {
+	ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error)
+}
+
+// DockerResolver resolves image metadata from a local Docker daemon's image
+// store: the exact local-store lookup the containers page needs to answer
+// "what's actually running here?".
+type DockerResolver struct {
+	Client DockerImageInspector
+}
+
+// NewDockerResolver returns a Resolver backed by client's local image store.
+func NewDockerResolver(client DockerImageInspector) *DockerResolver {
+	return &DockerResolver{Client: client}
+}
+
+func (d *DockerResolver) Resolve(ref string) (*Info, error) {
+	inspect, _, err := d.Client.ImageInspectWithRaw(context.Background(), ref)
+	if err != nil {
+		return nil, &NotFoundError{Ref: ref}
+	}
+
+	digest := ref
+	if len(inspect.RepoDigests) > 0 {
+		digest = inspect.RepoDigests[0]
+	}
+
+	var labels map[string]string
+	if inspect.Config != nil {
+		labels = inspect.Config.Labels
+	}
+
+	return &Info{
+		Ref:    ref,
+		Digest: digest,
+		Labels: labels,
+		Layers: len(inspect.RootFS.Layers),
+	}, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2014 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+type fakeDockerClient struct {
+	inspect types.ImageInspect
+	err     error
+}
+
+func (f *fakeDockerClient) ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error) {
+	return f.inspect, nil, f.err
+}
+
+func TestDockerResolverResolve(t *testing.T) {
+	client := &fakeDockerClient{
+		inspect: types.ImageInspect{
+			RepoDigests: []string{"example.com/app@sha256:abc123"},
+			Config: &types.Config{
+				Labels: map[string]string{"maintainer": "team-x"},
+			},
+			RootFS: types.RootFS{Layers: []string{"layer1", "layer2", "layer3"}},
+		},
+	}
+	resolver := NewDockerResolver(client)
+
+	info, err := resolver.Resolve("example.com/app:latest")
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+	if info.Digest != "example.com/app@sha256:abc123" {
+		t.Errorf("Digest = %q, want %q", info.Digest, "example.com/app@sha256:abc123")
+	}
+	if info.Labels["maintainer"] != "team-x" {
+		t.Errorf("Labels[maintainer] = %q, want %q", info.Labels["maintainer"], "team-x")
+	}
+	if info.Layers != 3 {
+		t.Errorf("Layers = %d, want 3", info.Layers)
+	}
+}
+
+func TestDockerResolverNotFound(t *testing.T) {
+	client := &fakeDockerClient{err: errors.New("no such image")}
+	resolver := NewDockerResolver(client)
+
+	_, err := resolver.Resolve("example.com/app:latest")
+	if err == nil {
+		t.Fatal("Resolve() returned nil error, want a NotFoundError")
+	}
+	if _, ok := err.(*NotFoundError); !ok {
+		t.Errorf("Resolve() error = %T, want *NotFoundError", err)
+	}
+}
+
+func TestDockerResolverNoRepoDigests(t *testing.T) {
+	client := &fakeDockerClient{inspect: types.ImageInspect{}}
+	resolver := NewDockerResolver(client)
+
+	info, err := resolver.Resolve("example.com/app:latest")
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+	if info.Digest != "example.com/app:latest" {
+		t.Errorf("Digest = %q, want the ref itself when no RepoDigests are reported", info.Digest)
+	}
+}
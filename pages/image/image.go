@@ -0,0 +1,134 @@
+// Copyright 2014 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package image resolves the image metadata (digest, labels, layer count)
+// backing a running container, so the containers page can show what's
+// actually running without operators having to correlate against a
+// separate registry tool.
+package image
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Info is the image metadata surfaced on the containers page.
+type Info struct {
+	Ref    string
+	Digest string
+	Labels map[string]string
+	Layers int
+}
+
+// Resolver looks up Info for an image reference. Implementations back this
+// with the container runtime's local image store and, optionally, a remote
+// registry.
+type Resolver interface {
+	Resolve(ref string) (*Info, error)
+}
+
+// NotFoundError is returned by a Resolver when ref isn't present in its
+// backing store.
+type NotFoundError struct {
+	Ref string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("image %q not found", e.Ref)
+}
+
+type cacheEntry struct {
+	info      *Info
+	fetchedAt time.Time
+}
+
+// CachingResolver wraps a Resolver with a simple TTL cache so repeated page
+// loads for the same image don't re-hit the local store, or the network
+// when a remote fallback is configured, on every request.
+type CachingResolver struct {
+	resolver Resolver
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// NewCachingResolver returns a Resolver that serves cached Info for up to
+// ttl before re-resolving ref against resolver.
+func NewCachingResolver(resolver Resolver, ttl time.Duration) *CachingResolver {
+	return &CachingResolver{
+		resolver: resolver,
+		ttl:      ttl,
+		cache:    make(map[string]cacheEntry),
+	}
+}
+
+func (c *CachingResolver) Resolve(ref string) (*Info, error) {
+	c.mu.Lock()
+	entry, ok := c.cache[ref]
+	c.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < c.ttl {
+		return entry.info, nil
+	}
+
+	info, err := c.resolver.Resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[ref] = cacheEntry{info: info, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return info, nil
+}
+
+// FallbackResolver resolves against Local first and only consults Remote
+// when Local doesn't have the image and RemoteFallback is enabled. This
+// keeps remote-registry lookups opt-in, since most deployments only want to
+// trust the runtime's own local store.
+type FallbackResolver struct {
+	Local          Resolver
+	Remote         Resolver
+	RemoteFallback bool
+}
+
+func (f *FallbackResolver) Resolve(ref string) (*Info, error) {
+	info, err := f.Local.Resolve(ref)
+	if err == nil {
+		return info, nil
+	}
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || !f.RemoteFallback || f.Remote == nil {
+		return nil, err
+	}
+	return f.Remote.Resolve(ref)
+}
+
+// NewResolver builds the default image resolver for the containers page:
+// local Docker store lookups, cached for ttl, with a remote-registry lookup
+// as the fallback when remoteFallback is enabled and the image isn't (or is
+// no longer) in the local store.
+func NewResolver(client DockerImageInspector, remoteFallback bool, ttl time.Duration) Resolver {
+	var resolver Resolver = NewDockerResolver(client)
+	if remoteFallback {
+		resolver = &FallbackResolver{
+			Local:          resolver,
+			Remote:         RegistryResolver{},
+			RemoteFallback: true,
+		}
+	}
+	return NewCachingResolver(resolver, ttl)
+}
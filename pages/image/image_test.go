@@ -0,0 +1,124 @@
+// Copyright 2014 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeResolver struct {
+	calls int
+	info  *Info
+	err   error
+}
+
+func (f *fakeResolver) Resolve(ref string) (*Info, error) {
+	f.calls++
+	return f.info, f.err
+}
+
+func TestCachingResolverCachesWithinTTL(t *testing.T) {
+	fake := &fakeResolver{info: &Info{Ref: "img", Digest: "sha256:abc"}}
+	resolver := NewCachingResolver(fake, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		info, err := resolver.Resolve("img")
+		if err != nil {
+			t.Fatalf("Resolve() returned error: %v", err)
+		}
+		if info.Digest != "sha256:abc" {
+			t.Errorf("Resolve() = %+v, want digest sha256:abc", info)
+		}
+	}
+	if fake.calls != 1 {
+		t.Errorf("underlying resolver called %d times, want 1", fake.calls)
+	}
+}
+
+func TestCachingResolverRefetchesAfterTTL(t *testing.T) {
+	fake := &fakeResolver{info: &Info{Ref: "img", Digest: "sha256:abc"}}
+	resolver := NewCachingResolver(fake, -time.Second) // already expired
+
+	if _, err := resolver.Resolve("img"); err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+	if _, err := resolver.Resolve("img"); err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+	if fake.calls != 2 {
+		t.Errorf("underlying resolver called %d times, want 2", fake.calls)
+	}
+}
+
+func TestCachingResolverDoesNotCacheErrors(t *testing.T) {
+	fake := &fakeResolver{err: errors.New("not found")}
+	resolver := NewCachingResolver(fake, time.Minute)
+
+	if _, err := resolver.Resolve("img"); err == nil {
+		t.Fatal("Resolve() returned nil error, want not-found error")
+	}
+	if _, err := resolver.Resolve("img"); err == nil {
+		t.Fatal("Resolve() returned nil error, want not-found error")
+	}
+	if fake.calls != 2 {
+		t.Errorf("underlying resolver called %d times, want 2 (errors shouldn't be cached)", fake.calls)
+	}
+}
+
+func TestFallbackResolverPrefersLocal(t *testing.T) {
+	local := &fakeResolver{info: &Info{Ref: "img", Digest: "local"}}
+	remote := &fakeResolver{info: &Info{Ref: "img", Digest: "remote"}}
+	resolver := &FallbackResolver{Local: local, Remote: remote, RemoteFallback: true}
+
+	info, err := resolver.Resolve("img")
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+	if info.Digest != "local" {
+		t.Errorf("Resolve() = %+v, want digest local", info)
+	}
+	if remote.calls != 0 {
+		t.Errorf("remote resolver called %d times, want 0", remote.calls)
+	}
+}
+
+func TestFallbackResolverFallsBackWhenEnabled(t *testing.T) {
+	local := &fakeResolver{err: &NotFoundError{Ref: "img"}}
+	remote := &fakeResolver{info: &Info{Ref: "img", Digest: "remote"}}
+	resolver := &FallbackResolver{Local: local, Remote: remote, RemoteFallback: true}
+
+	info, err := resolver.Resolve("img")
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+	if info.Digest != "remote" {
+		t.Errorf("Resolve() = %+v, want digest remote", info)
+	}
+}
+
+func TestFallbackResolverDoesNotFallBackWhenDisabled(t *testing.T) {
+	local := &fakeResolver{err: &NotFoundError{Ref: "img"}}
+	remote := &fakeResolver{info: &Info{Ref: "img", Digest: "remote"}}
+	resolver := &FallbackResolver{Local: local, Remote: remote, RemoteFallback: false}
+
+	if _, err := resolver.Resolve("img"); err == nil {
+		t.Fatal("Resolve() returned nil error, want the local not-found error")
+	}
+	if remote.calls != 0 {
+		t.Errorf("remote resolver called %d times, want 0", remote.calls)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2014 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// RegistryResolver resolves image metadata directly from the remote
+// registry a reference names. It's meant to be used as FallbackResolver's
+// Remote, for when the local store doesn't have the image (or the image was
+// never pulled, just referenced) and drift-detection against the registry's
+// view of the tag is wanted.
+type RegistryResolver struct{}
+
+func (RegistryResolver) Resolve(ref string) (*Info, error) {
+	nameRef, err := name.ParseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	img, err := remote.Image(nameRef)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, err
+	}
+	return &Info{
+		Ref:    ref,
+		Digest: digest.String(),
+		Labels: cfg.Config.Labels,
+		Layers: len(layers),
+	}, nil
+}
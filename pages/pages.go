@@ -0,0 +1,40 @@
+// Copyright 2014 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pages
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/google/cadvisor/manager"
+)
+
+// RegisterHandlers installs the /containers/ page, and its /fs/{device}
+// drill-down, on mux.
+func RegisterHandlers(mux *http.ServeMux, m manager.Manager) error {
+	mux.HandleFunc(ContainersPage, func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		if isFsPagePath(r.URL.Path) {
+			err = serveContainerFsPage(m, w, r)
+		} else {
+			err = serveContainersPage(m, w, r)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			glog.Errorf("Failed to serve %q: %s", r.URL, err)
+		}
+	})
+	return nil
+}